@@ -0,0 +1,196 @@
+package log
+
+import (
+	"expvar"
+	"sync"
+	"sync/atomic"
+)
+
+// DropPolicy controls what EnableAsync does when its queue is full.
+type DropPolicy int
+
+const (
+	// Block makes the caller wait for room in the queue, same as a
+	// synchronous Logger would block on a slow writer.
+	Block DropPolicy = iota
+	// DropOldest discards the queue's oldest pending record to make room
+	// for the new one.
+	DropOldest
+	// DropNewest discards the record that just failed to enqueue.
+	DropNewest
+)
+
+// AsyncStats reports how an async Logger's queue has behaved so far.
+type AsyncStats struct {
+	Emitted uint64
+	Dropped uint64
+	Flushed uint64
+}
+
+var (
+	asyncExpvarOnce sync.Once
+	asyncExpvar     *expvar.Map
+)
+
+// logExpvar lazily publishes aggregate async counters under the "log"
+// expvar name, so operators can alarm on dropped records without wiring
+// anything up per Logger.
+func logExpvar() *expvar.Map {
+	asyncExpvarOnce.Do(func() {
+		asyncExpvar = expvar.NewMap("log")
+	})
+	return asyncExpvar
+}
+
+type asyncPipeline struct {
+	queue  chan []byte
+	policy DropPolicy
+	wg     sync.WaitGroup
+
+	emitted uint64
+	dropped uint64
+	flushed uint64
+}
+
+func newAsyncPipeline(out writer, bufSize int, policy DropPolicy) *asyncPipeline {
+	if bufSize < 1 {
+		bufSize = 1
+	}
+	p := &asyncPipeline{
+		queue:  make(chan []byte, bufSize),
+		policy: policy,
+	}
+	p.wg.Add(1)
+	go p.run(out)
+	return p
+}
+
+// writer is the subset of io.Writer the async pipeline needs; kept local so
+// this file doesn't have to import io just for the parameter type below.
+type writer interface {
+	Write(p []byte) (int, error)
+}
+
+func (p *asyncPipeline) run(out writer) {
+	defer p.wg.Done()
+	for line := range p.queue {
+		out.Write(line)
+		atomic.AddUint64(&p.flushed, 1)
+		logExpvar().Add("flushed", 1)
+	}
+}
+
+func (p *asyncPipeline) submit(line []byte) {
+	select {
+	case p.queue <- line:
+		atomic.AddUint64(&p.emitted, 1)
+		logExpvar().Add("emitted", 1)
+		return
+	default:
+	}
+
+	switch p.policy {
+	case Block:
+		p.queue <- line
+		atomic.AddUint64(&p.emitted, 1)
+		logExpvar().Add("emitted", 1)
+	case DropOldest:
+		select {
+		case <-p.queue:
+			atomic.AddUint64(&p.dropped, 1)
+			logExpvar().Add("dropped", 1)
+		default:
+		}
+		select {
+		case p.queue <- line:
+			atomic.AddUint64(&p.emitted, 1)
+			logExpvar().Add("emitted", 1)
+		default:
+			atomic.AddUint64(&p.dropped, 1)
+			logExpvar().Add("dropped", 1)
+		}
+	case DropNewest:
+		atomic.AddUint64(&p.dropped, 1)
+		logExpvar().Add("dropped", 1)
+	}
+}
+
+func (p *asyncPipeline) stats() AsyncStats {
+	return AsyncStats{
+		Emitted: atomic.LoadUint64(&p.emitted),
+		Dropped: atomic.LoadUint64(&p.dropped),
+		Flushed: atomic.LoadUint64(&p.flushed),
+	}
+}
+
+// close drains the queue and waits for every queued record to be flushed.
+func (p *asyncPipeline) close() {
+	close(p.queue)
+	p.wg.Wait()
+}
+
+// asyncSlot holds the optional async pipeline behind a pointer so
+// WithField/WithFields can copy a Logger by value without copying a mutex.
+type asyncSlot struct {
+	mu   sync.Mutex
+	pipe *asyncPipeline
+}
+
+// EnableAsync dispatches every subsequent record through a background
+// goroutine backed by a bufSize-deep queue, instead of writing inline under
+// the standard library logger's mutex. A full queue blocks the caller; use
+// EnableAsyncWithPolicy to drop instead.
+func (l *Logger) EnableAsync(bufSize int) {
+	l.EnableAsyncWithPolicy(bufSize, Block)
+}
+
+// EnableAsyncWithPolicy is EnableAsync with an explicit DropPolicy for a
+// full queue.
+func (l *Logger) EnableAsyncWithPolicy(bufSize int, policy DropPolicy) {
+	l.async.mu.Lock()
+	defer l.async.mu.Unlock()
+	if l.async.pipe != nil {
+		l.async.pipe.close()
+	}
+	l.async.pipe = newAsyncPipeline(l.out, bufSize, policy)
+}
+
+// DisableAsync drains any pending records and returns the Logger to
+// synchronous writes.
+func (l *Logger) DisableAsync() {
+	l.async.mu.Lock()
+	defer l.async.mu.Unlock()
+	if l.async.pipe == nil {
+		return
+	}
+	l.async.pipe.close()
+	l.async.pipe = nil
+}
+
+// Stats reports the async pipeline's emitted, dropped and flushed counts.
+// It returns a zero AsyncStats if async logging isn't enabled.
+func (l *Logger) Stats() AsyncStats {
+	l.async.mu.Lock()
+	pipe := l.async.pipe
+	l.async.mu.Unlock()
+	if pipe == nil {
+		return AsyncStats{}
+	}
+	return pipe.stats()
+}
+
+func EnableAsync(bufSize int) {
+	StdLog.EnableAsync(bufSize)
+}
+
+func EnableAsyncWithPolicy(bufSize int, policy DropPolicy) {
+	StdLog.EnableAsyncWithPolicy(bufSize, policy)
+}
+
+func DisableAsync() {
+	StdLog.DisableAsync()
+}
+
+func Stats() AsyncStats {
+	return StdLog.Stats()
+}
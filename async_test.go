@@ -0,0 +1,56 @@
+package log
+
+import (
+	"io"
+	"testing"
+	"time"
+)
+
+// slowWriter simulates a writer with nontrivial I/O latency (e.g. a disk or
+// network sink), so the benchmarks below show what EnableAsync buys callers
+// under contention rather than being dominated by io.Discard's near-zero
+// cost.
+type slowWriter struct{}
+
+func (slowWriter) Write(p []byte) (int, error) {
+	time.Sleep(50 * time.Microsecond)
+	return len(p), nil
+}
+
+func (slowWriter) Close() error { return nil }
+
+// BenchmarkLoggerSync exercises the default synchronous path under
+// contention: every goroutine blocks on the shared, slow writer in turn.
+func BenchmarkLoggerSync(b *testing.B) {
+	l := New(slowWriter{}, "", LEVEL_ALL)
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			l.Info("benchmark message")
+		}
+	})
+
+	b.StopTimer()
+	l.Close()
+}
+
+// BenchmarkLoggerAsync is BenchmarkLoggerSync with EnableAsync turned on, so
+// callers hand records off to a queue instead of blocking on the slow
+// writer themselves.
+func BenchmarkLoggerAsync(b *testing.B) {
+	l := New(slowWriter{}, "", LEVEL_ALL)
+	l.EnableAsync(4096)
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			l.Info("benchmark message")
+		}
+	})
+
+	// Close() drains the queue; excluded from the measured region since
+	// EnableAsync's whole point is to keep that latency off the hot path.
+	b.StopTimer()
+	l.Close()
+}
+
+var _ io.WriteCloser = slowWriter{}
@@ -0,0 +1,73 @@
+package log
+
+import (
+	"io"
+	"sync"
+)
+
+// Backend is an additional log sink a Logger can fan out to, on top of its
+// primary writer. Each backend owns its own level filter and formatter so,
+// for example, a file backend can log at LEVEL_DEBUG while a network backend
+// only ships LEVEL_WARN and above.
+type Backend interface {
+	Write(entry Entry) error
+	Close() error
+	SetLevel(LogLevel)
+}
+
+type baseBackend struct {
+	level     LogLevel
+	formatter Formatter
+}
+
+func (b *baseBackend) SetLevel(v LogLevel) {
+	b.level.Set(v)
+}
+
+func (b *baseBackend) disabled(t LogType) bool {
+	// TYPE_PANIC and the zero LogType (Print/Printf/Println) are never
+	// level-filtered, mirroring Logger.isDisabled and the fact that the
+	// Print family bypasses it entirely on the primary writer.
+	if t == TYPE_PANIC || t == 0 {
+		return false
+	}
+	return !b.level.Test(t)
+}
+
+// WriterBackend adapts any io.WriteCloser into a Backend.
+type WriterBackend struct {
+	baseBackend
+
+	mu  sync.Mutex
+	out io.WriteCloser
+}
+
+// NewWriterBackend wraps out so it can be registered with Logger.RegisterBackend.
+// A nil formatter defaults to TextFormatter.
+func NewWriterBackend(out io.WriteCloser, level LogLevel, formatter Formatter) *WriterBackend {
+	if formatter == nil {
+		formatter = TextFormatter{}
+	}
+	return &WriterBackend{
+		baseBackend: baseBackend{level: level, formatter: formatter},
+		out:         out,
+	}
+}
+
+func (w *WriterBackend) Write(entry Entry) error {
+	if w.disabled(entry.Level) {
+		return nil
+	}
+	b := w.formatter.Format(entry)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, err := w.out.Write(b)
+	return err
+}
+
+func (w *WriterBackend) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.out.Close()
+}
@@ -0,0 +1,14 @@
+package log
+
+import "time"
+
+// Entry is a single log record as it is handed to a Backend. Logger builds
+// one of these per call once at least one backend is registered, so the
+// fields below should stay cheap to populate on the hot path.
+type Entry struct {
+	Time    time.Time
+	Level   LogType
+	Message string
+	Caller  string
+	Fields  map[string]interface{}
+}
@@ -0,0 +1,68 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Formatter turns an Entry into the bytes a Backend writes out.
+type Formatter interface {
+	Format(entry Entry) []byte
+}
+
+// TextFormatter renders an Entry the same way the stock Logger output looks,
+// plus a sorted "key=value" tail for any structured fields.
+type TextFormatter struct{}
+
+func (TextFormatter) Format(entry Entry) []byte {
+	var b bytes.Buffer
+	b.WriteString(entry.Time.Format("2006/01/02 15:04:05"))
+	b.WriteString(" ")
+	if entry.Caller != "" {
+		b.WriteString(entry.Caller)
+		b.WriteString(": ")
+	}
+	b.WriteString(entry.Level.String())
+	b.WriteString(entry.Message)
+
+	if len(entry.Fields) > 0 {
+		keys := make([]string, 0, len(entry.Fields))
+		for k := range entry.Fields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(&b, " %s=%v", k, entry.Fields[k])
+		}
+	}
+
+	if b.Len() == 0 || b.Bytes()[b.Len()-1] != '\n' {
+		b.WriteString("\n")
+	}
+	return b.Bytes()
+}
+
+// JSONFormatter renders an Entry as a single JSON object per line.
+type JSONFormatter struct{}
+
+func (JSONFormatter) Format(entry Entry) []byte {
+	obj := make(map[string]interface{}, len(entry.Fields)+4)
+	for k, v := range entry.Fields {
+		obj[k] = v
+	}
+	obj["time"] = entry.Time.Format("2006-01-02T15:04:05.000Z07:00")
+	obj["level"] = strings.TrimSpace(entry.Level.String())
+	obj["message"] = entry.Message
+	if entry.Caller != "" {
+		obj["caller"] = entry.Caller
+	}
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return []byte(fmt.Sprintf("{\"level\":\"\\t[ERROR]\",\"message\":\"log: failed to marshal entry: %s\"}\n", err))
+	}
+	return append(data, '\n')
+}
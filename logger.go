@@ -7,8 +7,11 @@ import (
 	"log"
 	"os"
 	"path"
+	"runtime"
 	"strings"
+	"sync"
 	"sync/atomic"
+	"time"
 )
 
 const (
@@ -106,10 +109,24 @@ func NopCloser(w io.Writer) io.WriteCloser {
 	return &nopCloser{w}
 }
 
+// backendSet holds the extra backends a Logger fans out to. It is kept
+// behind a pointer so WithField/WithFields can cheaply derive a child
+// Logger (copy by value) that still shares the same backends.
+type backendSet struct {
+	mu       sync.RWMutex
+	backends map[string]Backend
+}
+
 type Logger struct {
 	out   io.WriteCloser
 	log   *log.Logger
 	level LogLevel
+
+	backends *backendSet
+	fields   map[string]interface{}
+
+	async   *asyncSlot
+	sampler *samplerSlot
 }
 
 var StdLog = New(NopCloser(os.Stdout), "", LEVEL_DEBUG)
@@ -120,12 +137,61 @@ func New(writer io.Writer, prefix string, level LogLevel) *Logger {
 		out = NopCloser(writer)
 	}
 	return &Logger{
-		out:   out,
-		log:   log.New(out, prefix, LstdFlags),
-		level: level,
+		out:      out,
+		log:      log.New(out, prefix, LstdFlags),
+		level:    level,
+		backends: &backendSet{backends: make(map[string]Backend)},
+		async:    &asyncSlot{},
+		sampler:  &samplerSlot{},
 	}
 }
 
+// RegisterBackend adds an extra sink that every subsequent record is also
+// written to, independently of the Logger's primary writer. Registering
+// under a name already in use closes the previous backend before replacing
+// it.
+func (l *Logger) RegisterBackend(name string, b Backend) {
+	l.backends.mu.Lock()
+	defer l.backends.mu.Unlock()
+	if old, ok := l.backends.backends[name]; ok {
+		old.Close()
+	}
+	l.backends.backends[name] = b
+}
+
+// UnregisterBackend closes and removes the named backend, if any.
+func (l *Logger) UnregisterBackend(name string) {
+	l.backends.mu.Lock()
+	defer l.backends.mu.Unlock()
+	if b, ok := l.backends.backends[name]; ok {
+		b.Close()
+		delete(l.backends.backends, name)
+	}
+}
+
+// WithField returns a child Logger that attaches key/val to every record it
+// emits, in addition to any fields already attached by its parent. The
+// parent Logger is left unchanged.
+func (l *Logger) WithField(key string, val interface{}) *Logger {
+	return l.WithFields(map[string]interface{}{key: val})
+}
+
+// WithFields returns a child Logger that attaches fields to every record it
+// emits, in addition to any fields already attached by its parent. The
+// parent Logger is left unchanged.
+func (l *Logger) WithFields(fields map[string]interface{}) *Logger {
+	merged := make(map[string]interface{}, len(l.fields)+len(fields))
+	for k, v := range l.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	child := *l
+	child.fields = merged
+	return &child
+}
+
 func OpenFile(path string) (*os.File, error) {
 	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0666)
 	return f, err
@@ -186,7 +252,23 @@ func (l *Logger) SetLevel(v LogLevel) {
 }
 
 func (l *Logger) Close() {
+	l.async.mu.Lock()
+	if l.async.pipe != nil {
+		l.async.pipe.close()
+		l.async.pipe = nil
+	}
+	l.async.mu.Unlock()
+
+	if s, ok := l.out.(interface{ Sync() error }); ok {
+		s.Sync()
+	}
 	l.out.Close()
+
+	l.backends.mu.Lock()
+	defer l.backends.mu.Unlock()
+	for _, b := range l.backends.backends {
+		b.Close()
+	}
 }
 
 func (l *Logger) isDisabled(t LogType) bool {
@@ -295,6 +377,22 @@ func (l *Logger) Println(v ...interface{}) {
 }
 
 func (l *Logger) output(traceskip int, t LogType, s string) error {
+	if t != TYPE_PANIC && t != 0 {
+		if sampler := l.sampler.get(); sampler != nil {
+			caller := ""
+			if _, file, line, ok := runtime.Caller(traceskip + 1); ok {
+				caller = fmt.Sprintf("%s:%d", path.Base(file), line)
+			}
+			allow, suppressed := sampler.Allow(t, caller)
+			if !allow {
+				return nil
+			}
+			if suppressed > 0 {
+				s = strings.TrimRight(s, "\n") + fmt.Sprintf(" (sampled %d similar messages)", suppressed)
+			}
+		}
+	}
+
 	var b bytes.Buffer
 	fmt.Fprint(&b, t, s)
 
@@ -304,7 +402,46 @@ func (l *Logger) output(traceskip int, t LogType, s string) error {
 
 	//fmt.Println(b.String())
 	//return nil
-	return l.log.Output(traceskip+2, b.String())
+	var err error
+	l.async.mu.Lock()
+	pipe := l.async.pipe
+	l.async.mu.Unlock()
+	if pipe != nil {
+		var line bytes.Buffer
+		log.New(&line, l.log.Prefix(), l.log.Flags()).Output(traceskip+2, b.String())
+		pipe.submit(line.Bytes())
+	} else {
+		err = l.log.Output(traceskip+2, b.String())
+	}
+
+	l.backends.mu.RLock()
+	n := len(l.backends.backends)
+	l.backends.mu.RUnlock()
+	if n > 0 {
+		l.writeBackends(traceskip, t, s)
+	}
+
+	return err
+}
+
+func (l *Logger) writeBackends(traceskip int, t LogType, s string) {
+	caller := ""
+	if _, file, line, ok := runtime.Caller(traceskip + 2); ok {
+		caller = fmt.Sprintf("%s:%d", path.Base(file), line)
+	}
+	entry := Entry{
+		Time:    time.Now(),
+		Level:   t,
+		Message: strings.TrimRight(s, "\n"),
+		Caller:  caller,
+		Fields:  l.fields,
+	}
+
+	l.backends.mu.RLock()
+	defer l.backends.mu.RUnlock()
+	for _, b := range l.backends.backends {
+		b.Write(entry)
+	}
 }
 
 func Flags() int {
@@ -327,6 +464,22 @@ func SetLevel(v LogLevel) {
 	StdLog.level.Set(v)
 }
 
+func RegisterBackend(name string, b Backend) {
+	StdLog.RegisterBackend(name, b)
+}
+
+func UnregisterBackend(name string) {
+	StdLog.UnregisterBackend(name)
+}
+
+func WithField(key string, val interface{}) *Logger {
+	return StdLog.WithField(key, val)
+}
+
+func WithFields(fields map[string]interface{}) *Logger {
+	return StdLog.WithFields(fields)
+}
+
 func Panic(v ...interface{}) {
 	t := TYPE_PANIC
 	s := fmt.Sprint(v...)
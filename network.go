@@ -0,0 +1,116 @@
+package log
+
+import (
+	"errors"
+	"io"
+	"net"
+	"os"
+	"sync"
+)
+
+var ErrClosedNetworkWriter = errors.New("network writer is closed")
+
+// maxNetworkWriterRetry bounds the in-memory buffer networkWriter keeps for
+// lines it couldn't ship while the remote endpoint was down.
+const maxNetworkWriterRetry = 256
+
+// networkWriter ships raw lines over a TCP or UDP connection. If reconnect
+// is set, a write that fails (or finds the connection already down) is
+// buffered and retried once the connection is re-established; either way the
+// line is also written to stderr so nothing is silently lost while the
+// remote endpoint is unreachable.
+type networkWriter struct {
+	mu sync.Mutex
+
+	network   string
+	addr      string
+	reconnect bool
+
+	conn     net.Conn
+	fallback bool
+	retry    [][]byte
+	closed   bool
+}
+
+// NewNetworkWriter dials network/addr (e.g. "tcp", "udp") and returns a
+// writer that ships each Write to it. If the endpoint can't be reached at
+// construction time, or a later write fails, the writer degrades to stderr
+// rather than returning an error; when reconnect is true it also keeps
+// retrying the connection and replays buffered lines once it succeeds.
+func NewNetworkWriter(network, addr string, reconnect bool) io.WriteCloser {
+	w := &networkWriter{network: network, addr: addr, reconnect: reconnect}
+	if err := w.dial(); err != nil {
+		Errorf("log: network endpoint %s unreachable, falling back to stderr: %s", addr, err)
+		w.fallback = true
+	}
+	return w
+}
+
+func (w *networkWriter) dial() error {
+	conn, err := net.Dial(w.network, w.addr)
+	if err != nil {
+		return err
+	}
+	w.conn = conn
+	w.fallback = false
+	return nil
+}
+
+func (w *networkWriter) buffer(b []byte) {
+	if len(w.retry) >= maxNetworkWriterRetry {
+		w.retry = w.retry[1:]
+	}
+	line := make([]byte, len(b))
+	copy(line, b)
+	w.retry = append(w.retry, line)
+}
+
+func (w *networkWriter) flushRetry() {
+	for _, line := range w.retry {
+		w.conn.Write(line)
+	}
+	w.retry = nil
+}
+
+func (w *networkWriter) Write(b []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, ErrClosedNetworkWriter
+	}
+
+	if w.fallback || w.conn == nil {
+		if !w.reconnect {
+			return os.Stderr.Write(b)
+		}
+		if err := w.dial(); err != nil {
+			w.buffer(b)
+			return os.Stderr.Write(b)
+		}
+		w.flushRetry()
+	}
+
+	n, err := w.conn.Write(b)
+	if err != nil {
+		w.conn.Close()
+		w.conn = nil
+		w.fallback = true
+		if w.reconnect {
+			w.buffer(b)
+		}
+		os.Stderr.Write(b)
+		return len(b), nil
+	}
+	return n, nil
+}
+
+func (w *networkWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+	if w.conn != nil {
+		return w.conn.Close()
+	}
+	return nil
+}
@@ -6,7 +6,9 @@ import (
 	"io"
 	"os"
 	"path"
+	"strings"
 	"sync"
+	"time"
 )
 
 type rollingFile struct {
@@ -17,15 +19,32 @@ type rollingFile struct {
 	maxFileFrag int
 	maxFragSize int64
 
+	daily   bool
+	maxDays int
+	perm    os.FileMode
+
 	file     *os.File
 	basePath string
 	filePath string
 	fileFrag int
 	fragSize int64
+	openDate time.Time
 }
 
 var ErrClosedRollingFile = errors.New("rolling file is closed")
 
+// RollingOptions configures NewRollingFileWithOptions. MaxFragSize and
+// MaxFileFrag behave as in NewRollingFile; Daily additionally rotates the
+// current fragment once the calendar day changes, and MaxDays, when
+// positive, deletes dated fragments older than that many days.
+type RollingOptions struct {
+	MaxFragSize int64
+	MaxFileFrag int
+	Daily       bool
+	MaxDays     int
+	Perm        os.FileMode
+}
+
 func (r *rollingFile) rollingName() error {
 	var maxFileFrag = r.maxFileFrag - 1
 	maxFilePath := fmt.Sprintf("%s.%d.log", r.basePath, maxFileFrag)
@@ -46,23 +65,88 @@ func (r *rollingFile) rollingName() error {
 	return nil
 }
 
+// rollingDate renames the current fragment to basePath.YYYY-MM-DD.log,
+// dated by the day the fragment was opened on, and purges any dated
+// fragments older than MaxDays.
+func (r *rollingFile) rollingDate() error {
+	datedPath := fmt.Sprintf("%s.%s.log", r.basePath, r.openDate.Format("2006-01-02"))
+	err := os.Rename(r.filePath, datedPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	r.purgeExpired()
+	return nil
+}
+
+func (r *rollingFile) purgeExpired() {
+	if r.maxDays <= 0 {
+		return
+	}
+
+	dir, base := path.Split(r.basePath)
+	if dir == "" {
+		dir = "."
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	prefix := base + "."
+	cutoff := time.Now().AddDate(0, 0, -r.maxDays)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name := entry.Name()
+		if !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ".log") {
+			continue
+		}
+		datePart := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ".log")
+		date, err := time.Parse("2006-01-02", datePart)
+		if err != nil {
+			continue
+		}
+		if date.Before(cutoff) {
+			os.Remove(path.Join(dir, name))
+		}
+	}
+}
+
+// sameDate reports whether a and b fall on the same calendar day,
+// including the year, so rotation isn't skipped on the anniversary of the
+// day a long-running process opened its log file.
+func sameDate(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
+
 func (r *rollingFile) roll() error {
 	var rolling bool
+	var dateChanged bool
 
 	if r.file != nil {
-		if r.fragSize < r.maxFragSize {
+		dateChanged = r.daily && !sameDate(time.Now(), r.openDate)
+		if !dateChanged && r.fragSize < r.maxFragSize {
 			return nil
 		}
 		r.file.Close()
 		r.file = nil
 		r.fragSize = 0
-		rolling = true
+		if !dateChanged {
+			rolling = true
+		}
 	} else {
 		fi, err := os.Stat(r.filePath)
 		if err == nil {
-			fileSize := fi.Size()
-			if fileSize < r.maxFragSize {
-				r.fragSize = fileSize
+			if r.daily && !sameDate(fi.ModTime(), time.Now()) {
+				r.openDate = fi.ModTime()
+				dateChanged = true
+				r.fragSize = 0
+			} else if fi.Size() < r.maxFragSize {
+				r.fragSize = fi.Size()
 			} else {
 				r.fragSize = 0
 				rolling = true
@@ -70,20 +154,23 @@ func (r *rollingFile) roll() error {
 		}
 	}
 
-	if rolling {
-		err := r.rollingName()
-		if err != nil {
+	if dateChanged {
+		if err := r.rollingDate(); err != nil {
+			return err
+		}
+	} else if rolling {
+		if err := r.rollingName(); err != nil {
 			return err
 		}
 	}
 
-	f, err := os.OpenFile(r.filePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+	f, err := os.OpenFile(r.filePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, r.perm)
 	if err != nil {
 		return err
-	} else {
-		r.file = f
-		return nil
 	}
+	r.file = f
+	r.openDate = time.Now()
+	return nil
 }
 
 func (r *rollingFile) Close() error {
@@ -124,11 +211,21 @@ func (r *rollingFile) Write(b []byte) (int, error) {
 }
 
 func NewRollingFile(basePath string, maxFileFrag int, maxFragSize int64) (io.WriteCloser, error) {
-	if maxFileFrag <= 0 {
-		return nil, fmt.Errorf("invalid max file-frag = %d", maxFileFrag)
+	return NewRollingFileWithOptions(basePath, RollingOptions{
+		MaxFileFrag: maxFileFrag,
+		MaxFragSize: maxFragSize,
+		Perm:        0666,
+	})
+}
+
+// NewRollingFileWithOptions is like NewRollingFile but additionally supports
+// daily rotation and retention via RollingOptions.
+func NewRollingFileWithOptions(basePath string, opts RollingOptions) (io.WriteCloser, error) {
+	if opts.MaxFileFrag <= 0 {
+		return nil, fmt.Errorf("invalid max file-frag = %d", opts.MaxFileFrag)
 	}
-	if maxFragSize <= 0 {
-		return nil, fmt.Errorf("invalid max frag-size = %d", maxFragSize)
+	if opts.MaxFragSize <= 0 {
+		return nil, fmt.Errorf("invalid max frag-size = %d", opts.MaxFragSize)
 	}
 
 	dir, file := path.Split(basePath)
@@ -141,15 +238,29 @@ func NewRollingFile(basePath string, maxFileFrag int, maxFragSize int64) (io.Wri
 		return nil, err
 	}
 
+	perm := opts.Perm
+	if perm == 0 {
+		perm = 0666
+	}
+
 	fileFrag := 0
 	filePath := fmt.Sprintf("%s.%d.log", basePath, fileFrag)
 
-	return &rollingFile{
-		maxFileFrag: maxFileFrag,
-		maxFragSize: maxFragSize,
+	r := &rollingFile{
+		maxFileFrag: opts.MaxFileFrag,
+		maxFragSize: opts.MaxFragSize,
+		daily:       opts.Daily,
+		maxDays:     opts.MaxDays,
+		perm:        perm,
 
 		basePath: basePath,
 		filePath: filePath,
 		fileFrag: fileFrag,
-	}, nil
+	}
+
+	if r.daily {
+		r.purgeExpired()
+	}
+
+	return r, nil
 }
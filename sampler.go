@@ -0,0 +1,163 @@
+package log
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Sampler decides whether a record at the given level, from the given
+// caller (file:line), should be emitted. When Allow returns true after a
+// run of suppressed records for the same key, suppressed reports how many
+// were dropped so the caller can be told.
+type Sampler interface {
+	Allow(level LogType, caller string) (ok bool, suppressed int)
+}
+
+// samplerSlot holds the optional Sampler behind a pointer so
+// WithField/WithFields can copy a Logger by value safely.
+type samplerSlot struct {
+	v atomic.Value // holds samplerHolder
+}
+
+type samplerHolder struct {
+	sampler Sampler
+}
+
+func (s *samplerSlot) set(sampler Sampler) {
+	s.v.Store(samplerHolder{sampler})
+}
+
+func (s *samplerSlot) get() Sampler {
+	v := s.v.Load()
+	if v == nil {
+		return nil
+	}
+	return v.(samplerHolder).sampler
+}
+
+// SetSampler installs sampler on the Logger. Every subsequent Error/Warn/
+// Info/Debug call is run past it before being written; TYPE_PANIC and
+// Print-family records are never sampled. A nil sampler disables sampling.
+func (l *Logger) SetSampler(sampler Sampler) {
+	l.sampler.set(sampler)
+}
+
+func SetSampler(sampler Sampler) {
+	StdLog.SetSampler(sampler)
+}
+
+// tokenBucketSampler applies a single global rate limit across every
+// caller and level.
+type tokenBucketSampler struct {
+	mu sync.Mutex
+
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+
+	dropped int64
+}
+
+// NewTokenBucketSampler allows up to rps records per second on average,
+// with bursts of up to burst records.
+func NewTokenBucketSampler(rps int, burst int) Sampler {
+	return &tokenBucketSampler{
+		rate:  float64(rps),
+		burst: float64(burst),
+
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+func (s *tokenBucketSampler) Allow(level LogType, caller string) (bool, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.tokens += now.Sub(s.last).Seconds() * s.rate
+	if s.tokens > s.burst {
+		s.tokens = s.burst
+	}
+	s.last = now
+
+	if s.tokens < 1 {
+		s.dropped++
+		return false, 0
+	}
+
+	s.tokens--
+	suppressed := s.dropped
+	s.dropped = 0
+	return true, int(suppressed)
+}
+
+// burstState tracks one (caller, level) key's progress through the current
+// window.
+type burstState struct {
+	windowStart time.Time
+	count       int64
+	suppressed  int64
+}
+
+// burstSampler emits the first N records per (caller, level) key in every
+// window, then only every Mth record after that.
+type burstSampler struct {
+	mu sync.Mutex
+
+	first      int
+	thereafter int
+	window     time.Duration
+
+	state map[string]*burstState
+}
+
+// NewBurstSampler emits the first records-per-window messages for each
+// (caller, level) key, then every thereafter-th message until window
+// elapses and the key's count resets.
+func NewBurstSampler(first, thereafter int, window time.Duration) Sampler {
+	return &burstSampler{
+		first:      first,
+		thereafter: thereafter,
+		window:     window,
+		state:      make(map[string]*burstState),
+	}
+}
+
+func (s *burstSampler) Allow(level LogType, caller string) (bool, int) {
+	key := fmt.Sprintf("%d:%s", level, caller)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	st, ok := s.state[key]
+	if !ok || now.Sub(st.windowStart) >= s.window {
+		var carried int64
+		if ok {
+			carried = st.suppressed
+		}
+		st = &burstState{windowStart: now, suppressed: carried}
+		s.state[key] = st
+	}
+
+	st.count++
+	if st.count <= int64(s.first) {
+		suppressed := st.suppressed
+		st.suppressed = 0
+		return true, int(suppressed)
+	}
+
+	rem := st.count - int64(s.first)
+	if s.thereafter <= 0 || rem%int64(s.thereafter) != 0 {
+		st.suppressed++
+		return false, 0
+	}
+
+	suppressed := st.suppressed
+	st.suppressed = 0
+	return true, int(suppressed)
+}
@@ -0,0 +1,35 @@
+package log
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"runtime"
+	"testing"
+)
+
+type recordingSampler struct {
+	caller string
+}
+
+func (s *recordingSampler) Allow(level LogType, caller string) (bool, int) {
+	s.caller = caller
+	return true, 0
+}
+
+func TestSamplerReceivesRealCallSite(t *testing.T) {
+	l := New(NopCloser(io.Discard), "", LEVEL_ALL)
+	sampler := &recordingSampler{}
+	l.SetSampler(sampler)
+
+	_, file, line, ok := runtime.Caller(0)
+	l.Info("hello")
+	if !ok {
+		t.Fatal("runtime.Caller(0) failed")
+	}
+
+	want := fmt.Sprintf("%s:%d", path.Base(file), line+1)
+	if sampler.caller != want {
+		t.Fatalf("sampler saw caller %q, want %q", sampler.caller, want)
+	}
+}
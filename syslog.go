@@ -0,0 +1,179 @@
+package log
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// syslogFacilities maps the standard RFC5424 facility names to their
+// numeric codes.
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3,
+	"auth": 4, "syslog": 5, "lpr": 6, "news": 7,
+	"uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// syslogSeverity maps a LogType to its RFC5424 severity level.
+func syslogSeverity(t LogType) int {
+	switch t {
+	case TYPE_PANIC, TYPE_ERROR:
+		return 3
+	case TYPE_WARN:
+		return 4
+	case TYPE_DEBUG:
+		return 7
+	default:
+		return 6
+	}
+}
+
+// syslogWriter ships each record as an RFC5424 message over network/addr. If
+// the remote endpoint can't be reached, it falls back to writing to stderr
+// instead of failing construction.
+type syslogWriter struct {
+	mu sync.Mutex
+
+	conn     net.Conn
+	facility int
+	tag      string
+	hostname string
+	fallback bool
+}
+
+func newSyslogWriter(network, addr, facility, tag string) *syslogWriter {
+	fac, ok := syslogFacilities[strings.ToLower(facility)]
+	if !ok {
+		fac = syslogFacilities["user"]
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+
+	w := &syslogWriter{
+		facility: fac,
+		tag:      tag,
+		hostname: hostname,
+	}
+
+	conn, err := net.Dial(network, addr)
+	if err != nil {
+		Errorf("log: syslog endpoint %s unreachable, falling back to stderr: %s", addr, err)
+		w.fallback = true
+		return w
+	}
+	w.conn = conn
+	return w
+}
+
+// NewSyslogWriter dials network/addr (e.g. "udp", "syslog.internal:514") and
+// returns a writer that frames every record as an RFC5424 message. Plugged
+// in as a plain io.Writer this way, priority is guessed from the LogType
+// tag Logger.output renders into the line; register NewSyslogBackend
+// instead when the Logger also fans out to other backends so priority is
+// derived from the real LogType rather than sniffed text. facility is a
+// standard syslog facility name such as "local0" or "daemon"; unknown names
+// fall back to "user". If the endpoint can't be reached at construction
+// time, the writer degrades to stderr instead of returning an error.
+func NewSyslogWriter(network, addr, facility, tag string) io.WriteCloser {
+	return newSyslogWriter(network, addr, facility, tag)
+}
+
+// send frames payload as an RFC5424 message at the given priority and
+// writes it to the syslog connection, falling back to stderr on any error.
+// w.mu must be held.
+func (w *syslogWriter) send(pri int, payload string) error {
+	msg := fmt.Sprintf("<%d>1 %s %s %s %d - - %s\n",
+		pri, time.Now().Format(time.RFC3339), w.hostname, w.tag, os.Getpid(), payload)
+
+	if w.fallback || w.conn == nil {
+		_, err := os.Stderr.Write([]byte(msg))
+		return err
+	}
+
+	if _, err := w.conn.Write([]byte(msg)); err != nil {
+		w.fallback = true
+		_, err := os.Stderr.Write([]byte(msg))
+		return err
+	}
+	return nil
+}
+
+// textSeverity guesses a record's severity from the rendered LogType tag
+// (e.g. "\t[ERROR]") for callers that plug syslogWriter in as a bare
+// io.Writer and so have no Entry to read the real LogType from.
+func textSeverity(b []byte) int {
+	s := string(b)
+	switch {
+	case strings.Contains(s, TYPE_PANIC.String()), strings.Contains(s, TYPE_ERROR.String()):
+		return 3
+	case strings.Contains(s, TYPE_WARN.String()):
+		return 4
+	case strings.Contains(s, TYPE_DEBUG.String()):
+		return 7
+	default:
+		return 6
+	}
+}
+
+func (w *syslogWriter) Write(b []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	pri := w.facility*8 + textSeverity(b)
+	if err := w.send(pri, strings.TrimRight(string(b), "\n")); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+func (w *syslogWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn != nil {
+		return w.conn.Close()
+	}
+	return nil
+}
+
+// syslogBackend is a Backend that ships each Entry as an RFC5424 message,
+// deriving priority directly from entry.Level rather than from rendered
+// text, so it works correctly regardless of which Formatter other backends
+// on the same Logger use.
+type syslogBackend struct {
+	baseBackend
+	w *syslogWriter
+}
+
+// NewSyslogBackend is like NewSyslogWriter but returns a Backend for
+// RegisterBackend, with its own level filter and priority derived from
+// Entry.Level.
+func NewSyslogBackend(network, addr, facility, tag string, level LogLevel) Backend {
+	return &syslogBackend{
+		baseBackend: baseBackend{level: level},
+		w:           newSyslogWriter(network, addr, facility, tag),
+	}
+}
+
+func (b *syslogBackend) Write(entry Entry) error {
+	if b.disabled(entry.Level) {
+		return nil
+	}
+
+	b.w.mu.Lock()
+	defer b.w.mu.Unlock()
+	pri := b.w.facility*8 + syslogSeverity(entry.Level)
+	return b.w.send(pri, entry.Message)
+}
+
+func (b *syslogBackend) Close() error {
+	return b.w.Close()
+}